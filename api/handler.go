@@ -0,0 +1,15 @@
+package api
+
+import (
+	"github.com/go-playground/validator/v10"
+
+	"px-server/storage"
+)
+
+// Handler holds the dependencies every route needs: the storage backend,
+// the shared struct validator, and the change-feed hub.
+type Handler struct {
+	Store    storage.Store
+	Validate *validator.Validate
+	Hub      *Hub
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Healthz reports liveness: it returns 200 as soon as the process is up,
+// regardless of DB state, so Kubernetes doesn't restart a pod that's merely
+// waiting on a slow dependency.
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "ok")
+}
+
+// Readyz reports readiness: it pings the DB with a short timeout and
+// returns 503 when it's unreachable, so this service can sit behind
+// Kubernetes probes without a sidecar.
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	if err := h.Store.Ping(ctx); err != nil {
+		APIErrorResponse{Status: http.StatusServiceUnavailable, ErrMsg: "Database unreachable: " + err.Error()}.Write(w)
+		return
+	}
+	APIResponse{Status: http.StatusOK, Body: map[string]string{"status": "ok"}}.Write(w)
+}
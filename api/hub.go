@@ -0,0 +1,77 @@
+package api
+
+import (
+	"github.com/gorilla/websocket"
+
+	"px-server/models"
+)
+
+// Event is a single inventory change pushed to /nodes/watch subscribers.
+type Event struct {
+	Op   string      `json:"op"` // "upsert" or "delete"
+	Node models.Node `json:"node"`
+}
+
+// client is a single /nodes/watch websocket connection. send is buffered so
+// a slow reader doesn't block the hub; if it fills up, the client is
+// dropped rather than stalling every other subscriber.
+type client struct {
+	conn *websocket.Conn
+	send chan Event
+}
+
+// Hub fans out inventory change events to every connected /nodes/watch
+// client. It runs its own goroutine; Publish is the only method meant to be
+// called from request-handling goroutines.
+type Hub struct {
+	register   chan *client
+	unregister chan *client
+	broadcast  chan Event
+	clients    map[*client]bool
+}
+
+// NewHub creates a Hub and starts its run loop.
+func NewHub() *Hub {
+	h := &Hub{
+		register:   make(chan *client),
+		unregister: make(chan *client),
+		broadcast:  make(chan Event, 256),
+		clients:    make(map[*client]bool),
+	}
+	go h.run()
+	return h
+}
+
+func (h *Hub) run() {
+	for {
+		select {
+		case c := <-h.register:
+			h.clients[c] = true
+		case c := <-h.unregister:
+			h.drop(c)
+		case event := <-h.broadcast:
+			for c := range h.clients {
+				select {
+				case c.send <- event:
+				default:
+					// Slow consumer: drop it instead of blocking every other
+					// subscriber on a full buffer.
+					h.drop(c)
+				}
+			}
+		}
+	}
+}
+
+func (h *Hub) drop(c *client) {
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// Publish fans an event out to every connected client. Safe to call from
+// any goroutine.
+func (h *Hub) Publish(op string, n models.Node) {
+	h.broadcast <- Event{Op: op, Node: n}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"px-server/models"
+)
+
+// GetNode handles GET /node/:id.
+func (h *Handler) GetNode(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	node, err := h.Store.Get(ps.ByName("id"))
+	if err == models.ErrNodeNotFound {
+		APIErrorResponse{Status: http.StatusNotFound, ErrMsg: "Node not found"}.Write(w)
+		return
+	} else if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+	APIResponse{Status: http.StatusOK, Body: node}.Write(w)
+}
+
+// PutNode handles PUT /node/:id, upserting the node under the ID in the
+// path.
+func (h *Handler) PutNode(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var n models.Node
+	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Invalid JSON"}.Write(w)
+		return
+	}
+	n.NodeID = ps.ByName("id")
+
+	if err := h.Validate.Struct(n); err != nil {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Missing or invalid fields: " + err.Error()}.Write(w)
+		return
+	}
+
+	if err := h.Store.Upsert(n); err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+
+	log.Printf("Node %s saved successfully.", n.NodeID)
+	h.Hub.Publish("upsert", n)
+	APIResponse{Status: http.StatusOK, Body: n}.Write(w)
+}
+
+// DeleteNode handles DELETE /node/:id.
+func (h *Handler) DeleteNode(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id := ps.ByName("id")
+	err := h.Store.Delete(id)
+	if err == models.ErrNodeNotFound {
+		APIErrorResponse{Status: http.StatusNotFound, ErrMsg: "Node not found for deletion"}.Write(w)
+		return
+	} else if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+	h.Hub.Publish("delete", models.Node{NodeID: id})
+	APIResponse{Status: http.StatusOK, Body: map[string]string{"status": "deleted"}}.Write(w)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+
+	"px-server/models"
+)
+
+// nodesListResponse envelopes a page of nodes with the total row count
+// matching the filter, so clients paginating over large fleets don't have
+// to fetch every row to know when to stop.
+type nodesListResponse struct {
+	Total  int           `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+	Items  []models.Node `json:"items"`
+}
+
+// ListNodes handles GET /nodes, with optional ?limit=, ?offset=,
+// ?sort=nodeID|nodePxVersion, ?order=asc|desc, and field filters
+// (?nodeID=, ?nodeIP=, ?nodeKernel=, ?nodeOS=, ?nodePxVersion=).
+func (h *Handler) ListNodes(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	q := r.URL.Query()
+
+	opts := models.ListOptions{
+		Filter: models.NodeFilter{
+			NodeID:        q.Get("nodeID"),
+			NodeIP:        q.Get("nodeIP"),
+			NodeKernel:    q.Get("nodeKernel"),
+			NodeOS:        q.Get("nodeOS"),
+			NodePxVersion: q.Get("nodePxVersion"),
+		},
+		Sort:  q.Get("sort"),
+		Order: q.Get("order"),
+	}
+
+	if limit, err := strconv.Atoi(q.Get("limit")); err == nil {
+		opts.Limit = limit
+	}
+	if offset, err := strconv.Atoi(q.Get("offset")); err == nil {
+		opts.Offset = offset
+	}
+
+	nodes, total, err := h.Store.List(opts)
+	if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	APIResponse{Status: http.StatusOK, Body: nodesListResponse{
+		Total:  total,
+		Limit:  limit,
+		Offset: opts.Offset,
+		Items:  nodes,
+	}}.Write(w)
+}
+
+// CreateNode handles POST /nodes, bulk-upserting a JSON array of nodes in a
+// single transaction. Every row is validated up front; if any row fails
+// validation, nothing is written and the whole request is rejected.
+func (h *Handler) CreateNode(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var nodes []models.Node
+	if err := json.NewDecoder(r.Body).Decode(&nodes); err != nil {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Invalid JSON"}.Write(w)
+		return
+	}
+
+	if errs := validateNodes(h.Validate, nodes); len(errs) > 0 {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Missing or invalid fields: " + strings.Join(errs, "; ")}.Write(w)
+		return
+	}
+
+	results, err := h.Store.BulkUpsert(nodes)
+	if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+
+	log.Printf("Bulk upserted %d nodes.", len(nodes))
+	publishUpserts(h.Hub, nodes, results)
+	APIResponse{Status: bulkUpsertStatus(results), Body: results}.Write(w)
+}
+
+// publishUpserts fans out an "upsert" event for each node whose row
+// succeeded.
+func publishUpserts(hub *Hub, nodes []models.Node, results []models.NodeResult) {
+	for i, res := range results {
+		if res.Status == "ok" {
+			hub.Publish("upsert", nodes[i])
+		}
+	}
+}
+
+// bulkUpsertStatus picks the HTTP status for a BulkUpsert response: 201 if
+// every row succeeded, or 207 Multi-Status if some rows failed, so a client
+// doesn't have to guess at the partial-failure case from the body alone.
+func bulkUpsertStatus(results []models.NodeResult) int {
+	for _, res := range results {
+		if res.Status != "ok" {
+			return http.StatusMultiStatus
+		}
+	}
+	return http.StatusCreated
+}
+
+// validateNodes runs the struct validator over every node and returns one
+// "<nodeID>: <error>" string per failing row.
+func validateNodes(validate *validator.Validate, nodes []models.Node) []string {
+	var errs []string
+	for _, n := range nodes {
+		if err := validate.Struct(n); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", n.NodeID, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/csv"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	"px-server/models"
+)
+
+var nodeCSVHeader = []string{"nodeID", "nodeIP", "nodeKernel", "nodeOS", "nodePxVersion"}
+
+// ExportNodesCSV handles GET /nodes.csv, dumping the whole inventory as CSV
+// so operators can pull it into a spreadsheet for offline analysis.
+func (h *Handler) ExportNodesCSV(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	nodes, err := h.Store.ListAll()
+	if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="nodes.csv"`)
+
+	cw := csv.NewWriter(w)
+	cw.Write(nodeCSVHeader)
+	for _, n := range nodes {
+		cw.Write([]string{n.NodeID, n.NodeIP, n.NodeKernel, n.NodeOS, n.NodePxVersion})
+	}
+	cw.Flush()
+}
+
+// ImportNodesCSV handles POST /nodes.csv, seeding (or updating) the
+// inventory from a CSV dump in the same shape ExportNodesCSV produces. Every
+// row is validated before the bulk upsert runs, and the whole request is
+// rejected if any row fails.
+func (h *Handler) ImportNodesCSV(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	records, err := csv.NewReader(r.Body).ReadAll()
+	if err != nil {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Invalid CSV: " + err.Error()}.Write(w)
+		return
+	}
+	if len(records) == 0 {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Empty CSV"}.Write(w)
+		return
+	}
+
+	header := records[0]
+	if len(header) != len(nodeCSVHeader) {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Missing or invalid CSV header, expected columns: " + strings.Join(nodeCSVHeader, ",")}.Write(w)
+		return
+	}
+	for i, col := range nodeCSVHeader {
+		if header[i] != col {
+			APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Missing or invalid CSV header, expected columns: " + strings.Join(nodeCSVHeader, ",")}.Write(w)
+			return
+		}
+	}
+
+	rows := records[1:]
+	nodes := make([]models.Node, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != len(nodeCSVHeader) {
+			APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Malformed CSV row, expected columns: " + strings.Join(nodeCSVHeader, ",")}.Write(w)
+			return
+		}
+		nodes = append(nodes, models.Node{
+			NodeID:        row[0],
+			NodeIP:        row[1],
+			NodeKernel:    row[2],
+			NodeOS:        row[3],
+			NodePxVersion: row[4],
+		})
+	}
+
+	if errs := validateNodes(h.Validate, nodes); len(errs) > 0 {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Missing or invalid fields: " + strings.Join(errs, "; ")}.Write(w)
+		return
+	}
+
+	results, err := h.Store.BulkUpsert(nodes)
+	if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+
+	log.Printf("Imported %d nodes from CSV.", len(nodes))
+	publishUpserts(h.Hub, nodes, results)
+	APIResponse{Status: bulkUpsertStatus(results), Body: results}.Write(w)
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Response writes itself out as an HTTP response. Every handler returns one
+// so success and error bodies are shaped consistently across the API.
+type Response interface {
+	Write(w http.ResponseWriter)
+}
+
+// APIResponse is a successful response carrying a status code and a JSON
+// body.
+type APIResponse struct {
+	Status int
+	Body   interface{}
+}
+
+func (r APIResponse) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(r.Status)
+	json.NewEncoder(w).Encode(r.Body)
+}
+
+// APIErrorResponse is a failure response. It always serializes to
+// {"errmsg": "..."} so clients can rely on a single error shape.
+type APIErrorResponse struct {
+	Status int
+	ErrMsg string
+}
+
+func (r APIErrorResponse) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(r.Status)
+	json.NewEncoder(w).Encode(map[string]string{"errmsg": r.ErrMsg})
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+
+	"px-server/storage"
+)
+
+// Router builds the px-server HTTP route table.
+func Router(store storage.Store, validate *validator.Validate) *httprouter.Router {
+	h := &Handler{Store: store, Validate: validate, Hub: NewHub()}
+
+	router := httprouter.New()
+
+	router.GET("/node/:id", h.GetNode)
+	router.PUT("/node/:id", h.PutNode)
+	router.DELETE("/node/:id", h.DeleteNode)
+
+	router.GET("/nodes", h.ListNodes)
+	router.POST("/nodes", h.CreateNode)
+	router.GET("/nodes.csv", h.ExportNodesCSV)
+	router.POST("/nodes.csv", h.ImportNodesCSV)
+	router.GET("/nodes/watch", h.WatchNodes)
+
+	router.POST("/snapshot", h.SaveSnapshot)
+	router.GET("/snapshot", h.LoadSnapshot)
+
+	router.GET("/healthz", h.Healthz)
+	router.GET("/readyz", h.Readyz)
+
+	return router
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"px-server/models"
+)
+
+// SaveSnapshot handles POST /snapshot, freezing the inventory (or a filtered
+// subset of it via ?nodeOS= and/or ?nodePxVersion=) under a new opaque ID.
+func (h *Handler) SaveSnapshot(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	nodeOS := r.URL.Query().Get("nodeOS")
+	nodePxVersion := r.URL.Query().Get("nodePxVersion")
+
+	id, err := h.Store.SaveSnapshot(nodeOS, nodePxVersion)
+	if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+
+	log.Printf("Snapshot %s saved successfully.", id)
+	APIResponse{Status: http.StatusCreated, Body: map[string]string{"id": id}}.Write(w)
+}
+
+// LoadSnapshot handles GET /snapshot?id=..., returning the frozen JSON
+// exactly as it was saved.
+func (h *Handler) LoadSnapshot(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		APIErrorResponse{Status: http.StatusBadRequest, ErrMsg: "Missing id parameter"}.Write(w)
+		return
+	}
+
+	payload, err := h.Store.LoadSnapshot(id)
+	if err == models.ErrSnapshotNotFound {
+		APIErrorResponse{Status: http.StatusNotFound, ErrMsg: "Snapshot not found"}.Write(w)
+		return
+	} else if err != nil {
+		APIErrorResponse{Status: http.StatusInternalServerError, ErrMsg: "Database error: " + err.Error()}.Write(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(payload)
+}
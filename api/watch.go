@@ -0,0 +1,90 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/julienschmidt/httprouter"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Dashboards may be served from a different origin than px-server.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+const (
+	// pongWait is how long we'll wait for a pong before deciding the peer
+	// is gone; other incoming messages don't extend the read deadline.
+	pongWait = 60 * time.Second
+	// pingPeriod must be less than pongWait, so a ping lands and gets
+	// answered before the read deadline it's meant to refresh expires.
+	pingPeriod = (pongWait * 9) / 10
+	writeWait  = 10 * time.Second
+)
+
+// WatchNodes handles GET /nodes/watch, upgrading to a websocket that streams
+// {"op":"upsert"|"delete","node":{...}} events as the inventory changes.
+func (h *Handler) WatchNodes(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	c := &client{conn: conn, send: make(chan Event, 16)}
+	h.Hub.register <- c
+
+	go c.writePump()
+	c.readPump(h.Hub)
+}
+
+// readPump discards anything the client sends (this feed is one-way) and
+// exists purely to notice when the connection closes. A read deadline plus a
+// pong handler that extends it keeps a silently-dead peer (no TCP FIN, just
+// vanished) from blocking ReadMessage forever and leaking the client out of
+// Hub.clients.
+func (c *client) readPump(hub *Hub) {
+	defer func() {
+		hub.unregister <- c
+		c.conn.Close()
+	}()
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump relays events to the client and pings it periodically so
+// readPump's deadline keeps getting renewed on an otherwise-idle feed.
+func (c *client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-c.send:
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package models
+
+// NodeResult reports the outcome of a single row within a bulk upsert.
+type NodeResult struct {
+	NodeID string `json:"nodeID"`
+	Status string `json:"status"` // "ok" or "error"
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// NodeFilter restricts a node listing to rows matching every non-empty
+// field. Fields map 1:1 to Node's own fields.
+type NodeFilter struct {
+	NodeID        string
+	NodeIP        string
+	NodeKernel    string
+	NodeOS        string
+	NodePxVersion string
+}
+
+// ListOptions controls pagination, sorting, and filtering for a NodeStore's
+// List method.
+type ListOptions struct {
+	Filter NodeFilter
+	Sort   string // "nodeID" or "nodePxVersion"; defaults to nodeID
+	Order  string // "asc" or "desc"; defaults to asc
+	Limit  int    // defaults to 100
+	Offset int
+}
@@ -0,0 +1,16 @@
+package models
+
+import "errors"
+
+// Node is a single px fleet member as tracked in the nodes table.
+type Node struct {
+	NodeID        string `json:"nodeID" validate:"required"`
+	NodeIP        string `json:"nodeIP" validate:"required"`
+	NodeKernel    string `json:"nodeKernel" validate:"required"`
+	NodeOS        string `json:"nodeOS" validate:"required"`
+	NodePxVersion string `json:"nodePxVersion" validate:"required"`
+}
+
+// ErrNodeNotFound is returned by a NodeStore's Get and Delete when no row
+// matches the given node ID.
+var ErrNodeNotFound = errors.New("node not found")
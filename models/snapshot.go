@@ -0,0 +1,7 @@
+package models
+
+import "errors"
+
+// ErrSnapshotNotFound is returned by a Store's LoadSnapshot when no row
+// matches the given ID, or the row has expired.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
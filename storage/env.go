@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// envInt reads an integer env var, falling back to def if it is unset or
+// not a valid integer.
+func envInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// envDuration reads a time.Duration env var (e.g. "5m"), falling back to
+// def if it is unset or not a valid duration.
+func envDuration(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// configurePool applies the shared connection-pool env vars to db. The
+// MySQL and Postgres stores use it; the SQLite store does not, and pins its
+// pool to a single connection instead.
+func configurePool(db interface {
+	SetMaxOpenConns(int)
+	SetMaxIdleConns(int)
+	SetConnMaxLifetime(time.Duration)
+}) {
+	db.SetMaxOpenConns(envInt("PX_DB_MAX_OPEN_CONNS", 25))
+	db.SetMaxIdleConns(envInt("PX_DB_MAX_IDLE_CONNS", 25))
+	db.SetConnMaxLifetime(envDuration("PX_DB_CONN_MAX_LIFETIME", 5*time.Minute))
+}
@@ -0,0 +1,20 @@
+package storage
+
+import "fmt"
+
+// New opens the backend named by driver (reading driver-specific
+// configuration from the environment) and returns it as a Store. driver
+// should come from the PX_STORAGE_DRIVER env var; an empty string defaults
+// to "mysql" for backwards compatibility with earlier px-server versions.
+func New(driver string) (Store, error) {
+	switch driver {
+	case "", "mysql":
+		return NewMySQLStore()
+	case "postgres":
+		return NewPostgresStore()
+	case "sqlite":
+		return NewSQLiteStore()
+	default:
+		return nil, fmt.Errorf("unknown PX_STORAGE_DRIVER %q", driver)
+	}
+}
@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"px-server/models"
+)
+
+// MySQLStore is the original px-server backend: a single MySQL database
+// holding the nodes and snapshots tables.
+type MySQLStore struct {
+	db *sql.DB
+}
+
+// NewMySQLStore connects to MySQL using the MYSQL_* env vars (creating the
+// database if it doesn't exist), configures the connection pool, and
+// ensures the schema is present.
+func NewMySQLStore() (*MySQLStore, error) {
+	user := os.Getenv("MYSQL_USER")
+	pass := os.Getenv("MYSQL_PASSWORD")
+	host := os.Getenv("MYSQL_HOST")
+	port := os.Getenv("MYSQL_PORT")
+	dbName := os.Getenv("MYSQL_DB")
+	if user == "" || pass == "" || host == "" || port == "" || dbName == "" {
+		return nil, fmt.Errorf("missing required MySQL environment variables")
+	}
+
+	// Connect without a DB selected so we can create it if needed.
+	dsnNoDB := fmt.Sprintf("%s:%s@tcp(%s:%s)/", user, pass, host, port)
+	tmpDB, err := sql.Open("mysql", dsnNoDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL server: %w", err)
+	}
+	defer tmpDB.Close()
+	if err := tmpDB.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL server (ping failed): %w", err)
+	}
+	if _, err := tmpDB.Exec("CREATE DATABASE IF NOT EXISTS " + dbName); err != nil {
+		return nil, fmt.Errorf("failed to create database: %w", err)
+	}
+	log.Printf("Database %s created.", dbName)
+
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, dbName)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL DB: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping MySQL DB: %w", err)
+	}
+	log.Println("Connected to MySQL DB!")
+
+	configurePool(db)
+
+	if err := ensureMySQLSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &MySQLStore{db: db}, nil
+}
+
+func ensureMySQLSchema(db *sql.DB) error {
+	createNodesTable := `
+    CREATE TABLE IF NOT EXISTS nodes (
+        nodeID VARCHAR(64) PRIMARY KEY,
+        nodeIP VARCHAR(64),
+        nodeKernel VARCHAR(128),
+        nodeOS VARCHAR(128),
+        nodePxVersion VARCHAR(64)
+    )`
+	if _, err := db.Exec(createNodesTable); err != nil {
+		return err
+	}
+
+	createSnapshotsTable := `
+    CREATE TABLE IF NOT EXISTS snapshots (
+        id VARCHAR(32) PRIMARY KEY,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        payload JSON NOT NULL,
+        expires_at TIMESTAMP NULL
+    )`
+	if _, err := db.Exec(createSnapshotsTable); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *MySQLStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *MySQLStore) Upsert(n models.Node) error {
+	_, err := s.db.Exec(`INSERT INTO nodes (nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion)
+            VALUES (?, ?, ?, ?, ?)
+            ON DUPLICATE KEY UPDATE nodeIP=VALUES(nodeIP), nodeKernel=VALUES(nodeKernel), nodeOS=VALUES(nodeOS), nodePxVersion=VALUES(nodePxVersion)`,
+		n.NodeID, n.NodeIP, n.NodeKernel, n.NodeOS, n.NodePxVersion)
+	return err
+}
+
+func (s *MySQLStore) Get(nodeID string) (models.Node, error) {
+	var n models.Node
+	err := s.db.QueryRow(`SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes WHERE nodeID = ?`, nodeID).
+		Scan(&n.NodeID, &n.NodeIP, &n.NodeKernel, &n.NodeOS, &n.NodePxVersion)
+	if err == sql.ErrNoRows {
+		return models.Node{}, models.ErrNodeNotFound
+	}
+	return n, err
+}
+
+func (s *MySQLStore) Delete(nodeID string) error {
+	result, err := s.db.Exec(`DELETE FROM nodes WHERE nodeID = ?`, nodeID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrNodeNotFound
+	}
+	return nil
+}
+
+func (s *MySQLStore) ListAll() ([]models.Node, error) {
+	rows, err := s.db.Query(`SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes ORDER BY nodeID`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNodes(rows)
+}
+
+var sortableColumns = map[string]bool{
+	"nodeID":        true,
+	"nodePxVersion": true,
+}
+
+func nodeFilterClauses(f models.NodeFilter) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	if f.NodeID != "" {
+		clauses = append(clauses, "nodeID = ?")
+		args = append(args, f.NodeID)
+	}
+	if f.NodeIP != "" {
+		clauses = append(clauses, "nodeIP = ?")
+		args = append(args, f.NodeIP)
+	}
+	if f.NodeKernel != "" {
+		clauses = append(clauses, "nodeKernel = ?")
+		args = append(args, f.NodeKernel)
+	}
+	if f.NodeOS != "" {
+		clauses = append(clauses, "nodeOS = ?")
+		args = append(args, f.NodeOS)
+	}
+	if f.NodePxVersion != "" {
+		clauses = append(clauses, "nodePxVersion = ?")
+		args = append(args, f.NodePxVersion)
+	}
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+func (s *MySQLStore) List(opts models.ListOptions) ([]models.Node, int, error) {
+	where, args := nodeFilterClauses(opts.Filter)
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM nodes"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sortCol := opts.Sort
+	if !sortableColumns[sortCol] {
+		sortCol = "nodeID"
+	}
+	order := "ASC"
+	if strings.EqualFold(opts.Order, "desc") {
+		order = "DESC"
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := fmt.Sprintf(
+		"SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes%s ORDER BY %s %s LIMIT ? OFFSET ?",
+		where, sortCol, order)
+	queryArgs := append(append([]interface{}{}, args...), limit, opts.Offset)
+
+	rows, err := s.db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return nodes, total, nil
+}
+
+func scanNodes(rows *sql.Rows) ([]models.Node, error) {
+	var nodes []models.Node
+	for rows.Next() {
+		var n models.Node
+		if err := rows.Scan(&n.NodeID, &n.NodeIP, &n.NodeKernel, &n.NodeOS, &n.NodePxVersion); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+func (s *MySQLStore) BulkUpsert(nodes []models.Node) ([]models.NodeResult, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO nodes (nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion)
+            VALUES (?, ?, ?, ?, ?)
+            ON DUPLICATE KEY UPDATE nodeIP=VALUES(nodeIP), nodeKernel=VALUES(nodeKernel), nodeOS=VALUES(nodeOS), nodePxVersion=VALUES(nodePxVersion)`)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Every row gets its own result regardless of whether earlier or later
+	// rows failed, so callers can tell which rows actually landed instead
+	// of losing that detail behind a single batch-level error.
+	results := make([]models.NodeResult, len(nodes))
+	for i, n := range nodes {
+		if _, err := stmt.Exec(n.NodeID, n.NodeIP, n.NodeKernel, n.NodeOS, n.NodePxVersion); err != nil {
+			results[i] = models.NodeResult{NodeID: n.NodeID, Status: "error", Error: err.Error()}
+			continue
+		}
+		results[i] = models.NodeResult{NodeID: n.NodeID, Status: "ok"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *MySQLStore) SaveSnapshot(nodeOS, nodePxVersion string) (string, error) {
+	filter := models.NodeFilter{NodeOS: nodeOS, NodePxVersion: nodePxVersion}
+	where, args := nodeFilterClauses(filter)
+
+	rows, err := s.db.Query("SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes"+where, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(nodes)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := newSnapshotID()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.db.Exec(`INSERT INTO snapshots (id, payload) VALUES (?, ?)`, id, payload); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *MySQLStore) LoadSnapshot(id string) ([]byte, error) {
+	var payload []byte
+	err := s.db.QueryRow(`SELECT payload FROM snapshots WHERE id = ? AND (expires_at IS NULL OR expires_at > NOW())`, id).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrSnapshotNotFound
+	}
+	return payload, err
+}
+
+// newSnapshotID returns a URL-safe random identifier suitable for use as an
+// opaque snapshot ID in share links.
+func newSnapshotID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
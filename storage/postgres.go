@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+
+	"px-server/models"
+)
+
+// PostgresStore is a Postgres-backed NodeStore, selected via
+// PX_STORAGE_DRIVER=postgres.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore connects to Postgres using the standard PG* env vars,
+// configures the connection pool, and ensures the schema is present.
+func NewPostgresStore() (*PostgresStore, error) {
+	host := getenvDefault("PGHOST", "localhost")
+	port := getenvDefault("PGPORT", "5432")
+	user := os.Getenv("PGUSER")
+	pass := os.Getenv("PGPASSWORD")
+	dbName := os.Getenv("PGDATABASE")
+	if user == "" || dbName == "" {
+		return nil, fmt.Errorf("missing required PGUSER/PGDATABASE environment variables")
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		host, port, user, pass, dbName)
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Postgres: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping Postgres: %w", err)
+	}
+
+	configurePool(db)
+
+	if err := ensurePostgresSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &PostgresStore{db: db}, nil
+}
+
+func ensurePostgresSchema(db *sql.DB) error {
+	createNodesTable := `
+    CREATE TABLE IF NOT EXISTS nodes (
+        nodeID VARCHAR(64) PRIMARY KEY,
+        nodeIP VARCHAR(64),
+        nodeKernel VARCHAR(128),
+        nodeOS VARCHAR(128),
+        nodePxVersion VARCHAR(64)
+    )`
+	if _, err := db.Exec(createNodesTable); err != nil {
+		return err
+	}
+
+	createSnapshotsTable := `
+    CREATE TABLE IF NOT EXISTS snapshots (
+        id VARCHAR(32) PRIMARY KEY,
+        created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+        payload JSONB NOT NULL,
+        expires_at TIMESTAMPTZ NULL
+    )`
+	if _, err := db.Exec(createSnapshotsTable); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *PostgresStore) Upsert(n models.Node) error {
+	_, err := s.db.Exec(`INSERT INTO nodes (nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (nodeID) DO UPDATE SET
+                nodeIP = EXCLUDED.nodeIP, nodeKernel = EXCLUDED.nodeKernel,
+                nodeOS = EXCLUDED.nodeOS, nodePxVersion = EXCLUDED.nodePxVersion`,
+		n.NodeID, n.NodeIP, n.NodeKernel, n.NodeOS, n.NodePxVersion)
+	return err
+}
+
+func (s *PostgresStore) Get(nodeID string) (models.Node, error) {
+	var n models.Node
+	err := s.db.QueryRow(`SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes WHERE nodeID = $1`, nodeID).
+		Scan(&n.NodeID, &n.NodeIP, &n.NodeKernel, &n.NodeOS, &n.NodePxVersion)
+	if err == sql.ErrNoRows {
+		return models.Node{}, models.ErrNodeNotFound
+	}
+	return n, err
+}
+
+func (s *PostgresStore) Delete(nodeID string) error {
+	result, err := s.db.Exec(`DELETE FROM nodes WHERE nodeID = $1`, nodeID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrNodeNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) ListAll() ([]models.Node, error) {
+	rows, err := s.db.Query(`SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes ORDER BY nodeID`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNodes(rows)
+}
+
+// nodeFilterClausesPG builds a "WHERE ..." clause using $n placeholders
+// starting at startIdx, since Postgres doesn't support positional "?".
+func nodeFilterClausesPG(f models.NodeFilter, startIdx int) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	add := func(col, val string) {
+		args = append(args, val)
+		clauses = append(clauses, fmt.Sprintf("%s = $%d", col, startIdx+len(args)-1))
+	}
+	if f.NodeID != "" {
+		add("nodeID", f.NodeID)
+	}
+	if f.NodeIP != "" {
+		add("nodeIP", f.NodeIP)
+	}
+	if f.NodeKernel != "" {
+		add("nodeKernel", f.NodeKernel)
+	}
+	if f.NodeOS != "" {
+		add("nodeOS", f.NodeOS)
+	}
+	if f.NodePxVersion != "" {
+		add("nodePxVersion", f.NodePxVersion)
+	}
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+func (s *PostgresStore) List(opts models.ListOptions) ([]models.Node, int, error) {
+	where, args := nodeFilterClausesPG(opts.Filter, 1)
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM nodes"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sortCol := opts.Sort
+	if !sortableColumns[sortCol] {
+		sortCol = "nodeID"
+	}
+	order := "ASC"
+	if strings.EqualFold(opts.Order, "desc") {
+		order = "DESC"
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := fmt.Sprintf(
+		"SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes%s ORDER BY %s %s LIMIT $%d OFFSET $%d",
+		where, sortCol, order, len(args)+1, len(args)+2)
+	queryArgs := append(append([]interface{}{}, args...), limit, opts.Offset)
+
+	rows, err := s.db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return nodes, total, nil
+}
+
+const postgresUpsertQuery = `INSERT INTO nodes (nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (nodeID) DO UPDATE SET
+                nodeIP = EXCLUDED.nodeIP, nodeKernel = EXCLUDED.nodeKernel,
+                nodeOS = EXCLUDED.nodeOS, nodePxVersion = EXCLUDED.nodePxVersion`
+
+// BulkUpsert upserts every node in a single transaction, but gives each row
+// its own savepoint: Postgres aborts an entire transaction on any statement
+// error, so without a savepoint to roll back to, one bad row would poison
+// every row after it instead of just failing on its own.
+func (s *PostgresStore) BulkUpsert(nodes []models.Node) ([]models.NodeResult, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.NodeResult, len(nodes))
+	for i, n := range nodes {
+		savepoint := fmt.Sprintf("bulk_upsert_%d", i)
+		if _, err := tx.Exec("SAVEPOINT " + savepoint); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if _, err := tx.Exec(postgresUpsertQuery, n.NodeID, n.NodeIP, n.NodeKernel, n.NodeOS, n.NodePxVersion); err != nil {
+			results[i] = models.NodeResult{NodeID: n.NodeID, Status: "error", Error: err.Error()}
+			if _, rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint); rbErr != nil {
+				tx.Rollback()
+				return nil, rbErr
+			}
+			continue
+		}
+
+		if _, err := tx.Exec("RELEASE SAVEPOINT " + savepoint); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		results[i] = models.NodeResult{NodeID: n.NodeID, Status: "ok"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *PostgresStore) SaveSnapshot(nodeOS, nodePxVersion string) (string, error) {
+	filter := models.NodeFilter{NodeOS: nodeOS, NodePxVersion: nodePxVersion}
+	where, args := nodeFilterClausesPG(filter, 1)
+
+	rows, err := s.db.Query("SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes"+where, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(nodes)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := newSnapshotID()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.db.Exec(`INSERT INTO snapshots (id, payload) VALUES ($1, $2)`, id, payload); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *PostgresStore) LoadSnapshot(id string) ([]byte, error) {
+	var payload []byte
+	err := s.db.QueryRow(`SELECT payload FROM snapshots WHERE id = $1 AND (expires_at IS NULL OR expires_at > now())`, id).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrSnapshotNotFound
+	}
+	return payload, err
+}
+
+func getenvDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
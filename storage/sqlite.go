@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"px-server/models"
+)
+
+// SQLiteStore is a SQLite-backed NodeStore, selected via
+// PX_STORAGE_DRIVER=sqlite. It's meant for unit tests (point PX_SQLITE_PATH
+// at ":memory:") and single-binary deployments that don't want to run a
+// separate database.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens the file (or in-memory DB) named by PX_SQLITE_PATH
+// (defaulting to "px-server.db") and ensures the schema is present.
+func NewSQLiteStore() (*SQLiteStore, error) {
+	path := getenvDefault("PX_SQLITE_PATH", "px-server.db")
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite DB %q: %w", path, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping SQLite DB %q: %w", path, err)
+	}
+
+	// SQLite allows only one writer at a time; a second pooled connection
+	// just means concurrent writes fail with "database is locked" instead
+	// of queuing behind the first.
+	db.SetMaxOpenConns(1)
+
+	if err := ensureSQLiteSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func ensureSQLiteSchema(db *sql.DB) error {
+	createNodesTable := `
+    CREATE TABLE IF NOT EXISTS nodes (
+        nodeID TEXT PRIMARY KEY,
+        nodeIP TEXT,
+        nodeKernel TEXT,
+        nodeOS TEXT,
+        nodePxVersion TEXT
+    )`
+	if _, err := db.Exec(createNodesTable); err != nil {
+		return err
+	}
+
+	createSnapshotsTable := `
+    CREATE TABLE IF NOT EXISTS snapshots (
+        id TEXT PRIMARY KEY,
+        created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        payload TEXT NOT NULL,
+        expires_at DATETIME NULL
+    )`
+	if _, err := db.Exec(createSnapshotsTable); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLiteStore) Upsert(n models.Node) error {
+	_, err := s.db.Exec(`INSERT INTO nodes (nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion)
+            VALUES (?, ?, ?, ?, ?)
+            ON CONFLICT(nodeID) DO UPDATE SET
+                nodeIP=excluded.nodeIP, nodeKernel=excluded.nodeKernel,
+                nodeOS=excluded.nodeOS, nodePxVersion=excluded.nodePxVersion`,
+		n.NodeID, n.NodeIP, n.NodeKernel, n.NodeOS, n.NodePxVersion)
+	return err
+}
+
+func (s *SQLiteStore) Get(nodeID string) (models.Node, error) {
+	var n models.Node
+	err := s.db.QueryRow(`SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes WHERE nodeID = ?`, nodeID).
+		Scan(&n.NodeID, &n.NodeIP, &n.NodeKernel, &n.NodeOS, &n.NodePxVersion)
+	if err == sql.ErrNoRows {
+		return models.Node{}, models.ErrNodeNotFound
+	}
+	return n, err
+}
+
+func (s *SQLiteStore) Delete(nodeID string) error {
+	result, err := s.db.Exec(`DELETE FROM nodes WHERE nodeID = ?`, nodeID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrNodeNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListAll() ([]models.Node, error) {
+	rows, err := s.db.Query(`SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes ORDER BY nodeID`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNodes(rows)
+}
+
+func (s *SQLiteStore) List(opts models.ListOptions) ([]models.Node, int, error) {
+	where, args := nodeFilterClauses(opts.Filter)
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM nodes"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sortCol := opts.Sort
+	if !sortableColumns[sortCol] {
+		sortCol = "nodeID"
+	}
+	order := "ASC"
+	if strings.EqualFold(opts.Order, "desc") {
+		order = "DESC"
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := fmt.Sprintf(
+		"SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes%s ORDER BY %s %s LIMIT ? OFFSET ?",
+		where, sortCol, order)
+	queryArgs := append(append([]interface{}{}, args...), limit, opts.Offset)
+
+	rows, err := s.db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return nodes, total, nil
+}
+
+func (s *SQLiteStore) BulkUpsert(nodes []models.Node) ([]models.NodeResult, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO nodes (nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion)
+            VALUES (?, ?, ?, ?, ?)
+            ON CONFLICT(nodeID) DO UPDATE SET
+                nodeIP=excluded.nodeIP, nodeKernel=excluded.nodeKernel,
+                nodeOS=excluded.nodeOS, nodePxVersion=excluded.nodePxVersion`)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	defer stmt.Close()
+
+	// Every row gets its own result regardless of whether earlier or later
+	// rows failed, so callers can tell which rows actually landed instead
+	// of losing that detail behind a single batch-level error.
+	results := make([]models.NodeResult, len(nodes))
+	for i, n := range nodes {
+		if _, err := stmt.Exec(n.NodeID, n.NodeIP, n.NodeKernel, n.NodeOS, n.NodePxVersion); err != nil {
+			results[i] = models.NodeResult{NodeID: n.NodeID, Status: "error", Error: err.Error()}
+			continue
+		}
+		results[i] = models.NodeResult{NodeID: n.NodeID, Status: "ok"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *SQLiteStore) SaveSnapshot(nodeOS, nodePxVersion string) (string, error) {
+	filter := models.NodeFilter{NodeOS: nodeOS, NodePxVersion: nodePxVersion}
+	where, args := nodeFilterClauses(filter)
+
+	rows, err := s.db.Query("SELECT nodeID, nodeIP, nodeKernel, nodeOS, nodePxVersion FROM nodes"+where, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	nodes, err := scanNodes(rows)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(nodes)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := newSnapshotID()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.db.Exec(`INSERT INTO snapshots (id, payload) VALUES (?, ?)`, id, payload); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *SQLiteStore) LoadSnapshot(id string) ([]byte, error) {
+	var payload []byte
+	err := s.db.QueryRow(`SELECT payload FROM snapshots WHERE id = ? AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)`, id).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrSnapshotNotFound
+	}
+	return payload, err
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"px-server/models"
+)
+
+// newTestStore returns a fresh in-memory SQLite-backed store so tests don't
+// need a MySQL/Postgres container, per PX_SQLITE_PATH's documented purpose.
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	t.Setenv("PX_SQLITE_PATH", ":memory:")
+	s, err := NewSQLiteStore()
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { s.db.Close() })
+	return s
+}
+
+func TestSQLiteStoreUpsertGetDelete(t *testing.T) {
+	s := newTestStore(t)
+
+	n := models.Node{NodeID: "n1", NodeIP: "10.0.0.1", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "3.0"}
+	if err := s.Upsert(n); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	got, err := s.Get("n1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != n {
+		t.Fatalf("Get returned %+v, want %+v", got, n)
+	}
+
+	// Upsert again with a changed field to confirm it updates rather than
+	// erroring on the duplicate primary key.
+	n.NodeIP = "10.0.0.2"
+	if err := s.Upsert(n); err != nil {
+		t.Fatalf("Upsert (update): %v", err)
+	}
+	got, err = s.Get("n1")
+	if err != nil {
+		t.Fatalf("Get after update: %v", err)
+	}
+	if got.NodeIP != "10.0.0.2" {
+		t.Fatalf("Get after update returned NodeIP %q, want %q", got.NodeIP, "10.0.0.2")
+	}
+
+	if err := s.Delete("n1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("n1"); !errors.Is(err, models.ErrNodeNotFound) {
+		t.Fatalf("Get after delete returned err %v, want ErrNodeNotFound", err)
+	}
+	if err := s.Delete("n1"); !errors.Is(err, models.ErrNodeNotFound) {
+		t.Fatalf("Delete of missing row returned err %v, want ErrNodeNotFound", err)
+	}
+}
+
+func TestSQLiteStoreListPaginationFilterSort(t *testing.T) {
+	s := newTestStore(t)
+
+	nodes := []models.Node{
+		{NodeID: "c", NodeIP: "1.1.1.1", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+		{NodeID: "a", NodeIP: "1.1.1.2", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "2.0"},
+		{NodeID: "b", NodeIP: "1.1.1.3", NodeKernel: "5.15", NodeOS: "windows", NodePxVersion: "2.0"},
+	}
+	for _, n := range nodes {
+		if err := s.Upsert(n); err != nil {
+			t.Fatalf("Upsert(%s): %v", n.NodeID, err)
+		}
+	}
+
+	got, total, err := s.List(models.ListOptions{Sort: "nodeID", Order: "asc"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("total = %d, want 3", total)
+	}
+	var ids []string
+	for _, n := range got {
+		ids = append(ids, n.NodeID)
+	}
+	if want := []string{"a", "b", "c"}; !equalStrings(ids, want) {
+		t.Fatalf("List order = %v, want %v", ids, want)
+	}
+
+	got, total, err = s.List(models.ListOptions{Filter: models.NodeFilter{NodeOS: "windows"}})
+	if err != nil {
+		t.Fatalf("List (filtered): %v", err)
+	}
+	if total != 1 || len(got) != 1 || got[0].NodeID != "b" {
+		t.Fatalf("List (filtered) = %+v, total %d, want just node b", got, total)
+	}
+
+	got, total, err = s.List(models.ListOptions{Sort: "nodeID", Order: "asc", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("List (paginated): %v", err)
+	}
+	if total != 3 || len(got) != 1 || got[0].NodeID != "b" {
+		t.Fatalf("List (paginated) = %+v, total %d, want just node b with total 3", got, total)
+	}
+}
+
+func TestSQLiteStoreBulkUpsertAllSucceed(t *testing.T) {
+	s := newTestStore(t)
+
+	nodes := []models.Node{
+		{NodeID: "n1", NodeIP: "10.0.0.1", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+		{NodeID: "n2", NodeIP: "10.0.0.2", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+		{NodeID: "n3", NodeIP: "10.0.0.3", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+	}
+	results, err := s.BulkUpsert(nodes)
+	if err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+	if len(results) != len(nodes) {
+		t.Fatalf("got %d results, want %d", len(results), len(nodes))
+	}
+	for i, res := range results {
+		if res.Status != "ok" || res.NodeID != nodes[i].NodeID {
+			t.Fatalf("results[%d] = %+v, want ok for %s", i, res, nodes[i].NodeID)
+		}
+	}
+
+	all, err := s.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("ListAll returned %d rows, want 3", len(all))
+	}
+}
+
+// TestSQLiteStoreBulkUpsertPartialFailure forces one row to fail mid-batch
+// with a trigger that aborts just that INSERT, confirming the chunk0-5 fix:
+// BulkUpsert must keep going and commit every other row instead of rolling
+// the whole transaction back.
+func TestSQLiteStoreBulkUpsertPartialFailure(t *testing.T) {
+	s := newTestStore(t)
+
+	if _, err := s.db.Exec(`CREATE TRIGGER reject_bad_node BEFORE INSERT ON nodes
+		FOR EACH ROW WHEN NEW.nodeID = 'bad'
+		BEGIN SELECT RAISE(ABORT, 'forced failure for test'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	nodes := []models.Node{
+		{NodeID: "n1", NodeIP: "10.0.0.1", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+		{NodeID: "bad", NodeIP: "10.0.0.2", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+		{NodeID: "n3", NodeIP: "10.0.0.3", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+	}
+	results, err := s.BulkUpsert(nodes)
+	if err != nil {
+		t.Fatalf("BulkUpsert: %v", err)
+	}
+	if len(results) != len(nodes) {
+		t.Fatalf("got %d results, want %d", len(results), len(nodes))
+	}
+	if results[0].Status != "ok" || results[2].Status != "ok" {
+		t.Fatalf("results = %+v, want n1 and n3 ok", results)
+	}
+	if results[1].Status != "error" || results[1].Error == "" {
+		t.Fatalf("results[1] = %+v, want a populated error for the bad row", results[1])
+	}
+
+	all, err := s.ListAll()
+	if err != nil {
+		t.Fatalf("ListAll: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("ListAll returned %d rows, want 2 (bad row must not have committed)", len(all))
+	}
+	for _, n := range all {
+		if n.NodeID == "bad" {
+			t.Fatalf("ListAll contains the failed row %+v, want it rolled back", n)
+		}
+	}
+}
+
+func TestSQLiteStoreSnapshotRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+
+	nodes := []models.Node{
+		{NodeID: "n1", NodeIP: "10.0.0.1", NodeKernel: "5.15", NodeOS: "linux", NodePxVersion: "1.0"},
+		{NodeID: "n2", NodeIP: "10.0.0.2", NodeKernel: "5.15", NodeOS: "windows", NodePxVersion: "1.0"},
+	}
+	for _, n := range nodes {
+		if err := s.Upsert(n); err != nil {
+			t.Fatalf("Upsert(%s): %v", n.NodeID, err)
+		}
+	}
+
+	id, err := s.SaveSnapshot("linux", "1.0")
+	if err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	payload, err := s.LoadSnapshot(id)
+	if err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+	if len(payload) == 0 {
+		t.Fatalf("LoadSnapshot returned empty payload")
+	}
+
+	if _, err := s.LoadSnapshot("does-not-exist"); !errors.Is(err, models.ErrSnapshotNotFound) {
+		t.Fatalf("LoadSnapshot of missing id returned err %v, want ErrSnapshotNotFound", err)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+// Package storage provides a pluggable persistence layer for px-server's
+// node inventory, selected at startup by the PX_STORAGE_DRIVER env var.
+package storage
+
+import (
+	"context"
+
+	"px-server/models"
+)
+
+// NodeStore is the minimal surface handlers need for node CRUD, so they can
+// depend on an interface instead of a concrete *sql.DB and a specific SQL
+// dialect.
+type NodeStore interface {
+	Upsert(n models.Node) error
+	Get(nodeID string) (models.Node, error)
+	Delete(nodeID string) error
+	List(opts models.ListOptions) ([]models.Node, int, error)
+}
+
+// Store is the full backend contract, adding the bulk, export, snapshot,
+// and health operations px-server's handlers need on top of NodeStore.
+type Store interface {
+	NodeStore
+
+	BulkUpsert(nodes []models.Node) ([]models.NodeResult, error)
+	ListAll() ([]models.Node, error)
+
+	SaveSnapshot(nodeOS, nodePxVersion string) (string, error)
+	LoadSnapshot(id string) ([]byte, error)
+
+	Ping(ctx context.Context) error
+}